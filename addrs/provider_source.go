@@ -0,0 +1,175 @@
+package addrs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// DefaultProviderSourceHost is the hostname substituted for a provider
+// source address when no hostname is given explicitly.
+const DefaultProviderSourceHost = "registry.terraform.io"
+
+// DefaultProviderSourceNamespace is the namespace substituted for a
+// provider source address when no namespace is given explicitly.
+const DefaultProviderSourceNamespace = "hashicorp"
+
+// ProviderSourceAddr is the address of a provider as given in the "source"
+// argument of a required_providers entry, fully qualified to the
+// three-part "hostname/namespace/type" form.
+//
+// Hostname and Namespace may be omitted in the source string, in which
+// case they default to DefaultProviderSourceHost and
+// DefaultProviderSourceNamespace respectively, so two source strings that
+// normalize to the same ProviderSourceAddr refer to the same provider.
+type ProviderSourceAddr struct {
+	Hostname  string
+	Namespace string
+	Type      string
+}
+
+// String returns the fully-qualified source string for the address,
+// including any components that were defaulted during parsing.
+func (p ProviderSourceAddr) String() string {
+	return fmt.Sprintf("%s/%s/%s", p.Hostname, p.Namespace, p.Type)
+}
+
+// ForDisplay returns a version of the address suitable for inclusion in
+// diagnostic messages, eliding the hostname when it's the default
+// registry so that such addresses are shown in the shorter
+// "namespace/type" form. The result is always lower-case and always
+// includes the namespace, even if the original source string omitted it.
+func (p ProviderSourceAddr) ForDisplay() string {
+	if p.Hostname == DefaultProviderSourceHost {
+		return fmt.Sprintf("%s/%s", p.Namespace, p.Type)
+	}
+	return p.String()
+}
+
+// ParseProviderSourceAddr parses the given source string, as written in a
+// required_providers "source" argument, into a ProviderSourceAddr.
+//
+// A source string may give one, two, or three slash-separated components.
+// A single component is taken as the type name, with the hostname and
+// namespace defaulted; two components are taken as "namespace/type", with
+// only the hostname defaulted; three components give the hostname,
+// namespace, and type explicitly.
+//
+// subject, if non-nil, is used as the diagnostic subject range so callers
+// can point at the "source" subexpression that produced an invalid
+// address.
+func ParseProviderSourceAddr(source string, subject *hcl.Range) (ProviderSourceAddr, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	parts := strings.Split(source, "/")
+	for _, part := range parts {
+		if part == "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid provider source address",
+				Detail:   fmt.Sprintf("Provider source %q must not have empty path segments.", source),
+				Subject:  subject,
+			})
+			return ProviderSourceAddr{}, diags
+		}
+	}
+
+	var addr ProviderSourceAddr
+	switch len(parts) {
+	case 1:
+		addr = ProviderSourceAddr{
+			Hostname:  DefaultProviderSourceHost,
+			Namespace: DefaultProviderSourceNamespace,
+			Type:      parts[0],
+		}
+	case 2:
+		addr = ProviderSourceAddr{
+			Hostname:  DefaultProviderSourceHost,
+			Namespace: parts[0],
+			Type:      parts[1],
+		}
+	case 3:
+		addr = ProviderSourceAddr{
+			Hostname:  parts[0],
+			Namespace: parts[1],
+			Type:      parts[2],
+		}
+	default:
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider source address",
+			Detail:   fmt.Sprintf("Provider source %q must have the form \"[hostname/][namespace/]type\".", source),
+			Subject:  subject,
+		})
+		return ProviderSourceAddr{}, diags
+	}
+
+	if !validProviderSourceHostname(addr.Hostname) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider source address",
+			Detail:   fmt.Sprintf("Provider source %q has invalid hostname %q: must be a valid DNS name.", source, addr.Hostname),
+			Subject:  subject,
+		})
+	}
+	if !hclsyntax.ValidIdentifier(addr.Namespace) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider source address",
+			Detail:   fmt.Sprintf("Provider source %q has invalid namespace %q: must be a valid identifier.", source, addr.Namespace),
+			Subject:  subject,
+		})
+	}
+	if !hclsyntax.ValidIdentifier(addr.Type) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider source address",
+			Detail:   fmt.Sprintf("Provider source %q has invalid provider type %q: must be a valid identifier.", source, addr.Type),
+			Subject:  subject,
+		})
+	}
+
+	// Provider source addresses are case-insensitive, so we normalize to
+	// lowercase here. Without this, two declarations that differ only in
+	// case (e.g. "Hashicorp/test" and "hashicorp/test") would produce
+	// distinct ProviderSourceAddr values and so fail to dedupe/merge when
+	// used as a map key, even though they name the same provider.
+	addr.Hostname = strings.ToLower(addr.Hostname)
+	addr.Namespace = strings.ToLower(addr.Namespace)
+	addr.Type = strings.ToLower(addr.Type)
+
+	return addr, diags
+}
+
+func validProviderSourceHostname(host string) bool {
+	labels := strings.Split(host, ".")
+	if len(labels) == 0 {
+		return false
+	}
+	for _, label := range labels {
+		if !validDNSLabel(label) {
+			return false
+		}
+	}
+	return true
+}
+
+func validDNSLabel(label string) bool {
+	if label == "" {
+		return false
+	}
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			continue
+		case c == '-' && i != 0 && i != len(label)-1:
+			continue
+		default:
+			return false
+		}
+	}
+	return true
+}
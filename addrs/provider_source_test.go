@@ -0,0 +1,19 @@
+package addrs
+
+import "testing"
+
+func TestParseProviderSourceAddr_caseNormalization(t *testing.T) {
+	got, diags := ParseProviderSourceAddr("Hashicorp/Test", nil)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diags: %s", diags)
+	}
+
+	want, diags := ParseProviderSourceAddr("hashicorp/test", nil)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diags: %s", diags)
+	}
+
+	if got != want {
+		t.Fatalf("differently-cased source addresses did not normalize to the same value: got %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+package configschema
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hcldec"
+)
+
+// DecoderSpec returns a hcldec.Spec that can be used to decode a HCL Body
+// using the facilities in the hcldec package, producing a value that
+// conforms to the type returned by ImpliedType.
+func (b *Block) DecoderSpec() hcldec.Spec {
+	ret := hcldec.ObjectSpec{}
+	if b == nil {
+		return ret
+	}
+
+	for name, attrS := range b.Attributes {
+		ret[name] = &hcldec.AttrSpec{
+			Name:     name,
+			Type:     attrS.Type,
+			Required: attrS.Required,
+		}
+	}
+
+	for name, blockS := range b.BlockTypes {
+		childSpec := blockS.Block.DecoderSpec()
+
+		switch blockS.Nesting {
+		case NestingSingle, NestingGroup:
+			ret[name] = &hcldec.BlockSpec{
+				TypeName: name,
+				Nested:   childSpec,
+				Required: blockS.MinItems == 1 && blockS.MaxItems == 1,
+			}
+			if blockS.Nesting == NestingGroup {
+				// An absent group block decodes as if it were present but
+				// empty, with each of its attributes absent.
+				empty, _ := hcldec.Decode(hcl.EmptyBody(), childSpec, nil)
+				ret[name] = &hcldec.DefaultSpec{
+					Primary: ret[name],
+					Default: &hcldec.LiteralSpec{Value: empty},
+				}
+			}
+		case NestingList:
+			ret[name] = &hcldec.BlockListSpec{
+				TypeName: name,
+				Nested:   childSpec,
+				MinItems: blockS.MinItems,
+				MaxItems: blockS.MaxItems,
+			}
+		case NestingSet:
+			ret[name] = &hcldec.BlockSetSpec{
+				TypeName: name,
+				Nested:   childSpec,
+				MinItems: blockS.MinItems,
+				MaxItems: blockS.MaxItems,
+			}
+		case NestingMap:
+			ret[name] = &hcldec.BlockMapSpec{
+				TypeName:   name,
+				Nested:     childSpec,
+				LabelNames: []string{"key"},
+			}
+		default:
+			// Invalid nesting mode, so we'll just ignore this one. A
+			// caller constructing a schema this way is already misusing
+			// the API.
+		}
+	}
+
+	return ret
+}
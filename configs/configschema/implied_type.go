@@ -0,0 +1,45 @@
+package configschema
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ImpliedType returns the cty.Type that would result from decoding a
+// configuration block using the receiving schema.
+//
+// This is the same type that would be produced by DecoderSpec, and is
+// provided separately so that callers that just need the type (for
+// example, to build a placeholder value) don't need to build a decoder
+// spec first.
+func (b *Block) ImpliedType() cty.Type {
+	if b == nil {
+		return cty.EmptyObject
+	}
+
+	atys := make(map[string]cty.Type)
+
+	for name, attrS := range b.Attributes {
+		atys[name] = attrS.Type
+	}
+
+	for name, blockS := range b.BlockTypes {
+		aty := blockS.Block.ImpliedType()
+
+		switch blockS.Nesting {
+		case NestingSingle, NestingGroup:
+			atys[name] = aty
+		case NestingList:
+			atys[name] = cty.List(aty)
+		case NestingSet:
+			atys[name] = cty.Set(aty)
+		case NestingMap:
+			atys[name] = cty.Map(aty)
+		default:
+			// Invalid nesting mode, so we'll just ignore this one. A
+			// caller constructing a schema this way is already misusing
+			// the API, so this is only a defensive fallback.
+		}
+	}
+
+	return cty.Object(atys)
+}
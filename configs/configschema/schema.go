@@ -0,0 +1,73 @@
+// Package configschema contains types for describing the expected shape
+// of the configuration body for a Terraform provider, resource type, or
+// similar plugin-defined object, independent of any particular HCL
+// version or decoding mechanism.
+package configschema
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Block represents a configuration block whose body is described by a
+// set of attributes and nested block types.
+//
+// "Block", here, is a broader concept than an HCL block: it also
+// describes the overall shape of a configuration body that may be
+// implemented using attributes alone, such as a provider configuration.
+type Block struct {
+	Attributes map[string]*Attribute
+	BlockTypes map[string]*NestedBlock
+}
+
+// Attribute represents a configuration attribute, within a block, that
+// expects a value of a particular type.
+type Attribute struct {
+	Type        cty.Type
+	Description string
+	Required    bool
+	Optional    bool
+	Computed    bool
+	Sensitive   bool
+}
+
+// NestedBlock represents a nested block within a parent Block, with a
+// particular nesting mode that determines how many instances of it are
+// allowed and how they are represented in the resulting value.
+type NestedBlock struct {
+	Block
+
+	Nesting NestingMode
+
+	MinItems, MaxItems int
+}
+
+// NestingMode is an enumeration of the ways a NestedBlock can be nested
+// within its parent block.
+type NestingMode int
+
+const (
+	nestingModeInvalid NestingMode = iota
+
+	// NestingSingle indicates that only a single instance of a given
+	// block type is allowed, with no labels, and its content should be
+	// provided directly as an object value.
+	NestingSingle
+
+	// NestingGroup is like NestingSingle, but a nested block is implied
+	// even if not physically present in the configuration, with each of
+	// its own attributes treated as absent in that case.
+	NestingGroup
+
+	// NestingList indicates that multiple blocks of the given type are
+	// allowed, with the result appearing as a list of objects.
+	NestingList
+
+	// NestingSet indicates that multiple blocks of the given type are
+	// allowed, with the result appearing as a set of objects.
+	NestingSet
+
+	// NestingMap indicates that multiple blocks of the given type are
+	// allowed, each with a single label, and that they should be
+	// represented as a map of objects keyed on that label.
+	NestingMap
+)
@@ -0,0 +1,91 @@
+package configs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// Module is the top-level container for the declarations in a single
+// module, assembled from the set of files that make it up.
+//
+// This currently tracks only the subset of a module's declarations
+// related to provider configuration and requirements; the parser is
+// responsible for assembling the module's other declarations (resources,
+// variables, outputs, etc.) separately.
+type Module struct {
+	ProviderConfigs map[string]*Provider
+
+	// ProviderRequirements is keyed by the provider's fully-qualified
+	// source address, so that a single provider referenced under several
+	// local names (aliases) still resolves to one set of merged version
+	// constraints for installation purposes.
+	ProviderRequirements map[addrs.ProviderSourceAddr]*ProviderRequirement
+
+	// ProviderLocalNames maps each local name declared in a
+	// required_providers block back to the source address it identifies,
+	// for resolving provider references in expressions.
+	ProviderLocalNames map[string]addrs.ProviderSourceAddr
+}
+
+// NewModule returns an empty Module ready to be populated by one or more
+// calls to appendProviderRequirements and similar module-assembly helpers.
+func NewModule() *Module {
+	return &Module{
+		ProviderConfigs:      make(map[string]*Provider),
+		ProviderRequirements: make(map[addrs.ProviderSourceAddr]*ProviderRequirement),
+		ProviderLocalNames:   make(map[string]addrs.ProviderSourceAddr),
+	}
+}
+
+// appendProviderRequirements merges the requirements decoded from a single
+// required_providers block into the module. Requirements are deduplicated
+// by their fully-qualified source address, so that multiple local names
+// (aliases) declared against the same source contribute to one merged set
+// of version constraints, while each alias still gets its own entry in
+// ProviderLocalNames for expression resolution.
+func (m *Module) appendProviderRequirements(reqs []*ProviderRequirement) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for _, req := range reqs {
+		sourceAddr := req.SourceAddr
+		if sourceAddr == (addrs.ProviderSourceAddr{}) {
+			// No "source" argument was given, so the local name doubles
+			// as the provider type under the default registry host and
+			// namespace. Provider source addresses are case-insensitive
+			// (see addrs.ParseProviderSourceAddr), so we lower-case the
+			// local name here too, or else this implicit address
+			// wouldn't dedupe against an explicit, lower-cased "source".
+			sourceAddr = addrs.ProviderSourceAddr{
+				Hostname:  addrs.DefaultProviderSourceHost,
+				Namespace: addrs.DefaultProviderSourceNamespace,
+				Type:      strings.ToLower(req.Name),
+			}
+		}
+
+		if existingAddr, exists := m.ProviderLocalNames[req.Name]; exists && existingAddr != sourceAddr {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate required_providers entry",
+				Detail:   fmt.Sprintf("A provider requirement for local name %q was already declared, pointing at a different source address (%s).", req.Name, existingAddr),
+				Subject:  req.DeclRange.Ptr(),
+			})
+			continue
+		}
+		m.ProviderLocalNames[req.Name] = sourceAddr
+
+		existing, exists := m.ProviderRequirements[sourceAddr]
+		if !exists {
+			merged := *req
+			merged.SourceAddr = sourceAddr
+			m.ProviderRequirements[sourceAddr] = &merged
+			continue
+		}
+		existing.VersionConstraints = append(existing.VersionConstraints, req.VersionConstraints...)
+	}
+
+	return diags
+}
@@ -0,0 +1,57 @@
+package configs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// mergeProviderBlocks adds the given "provider" blocks, gathered across
+// every file in a module, to the receiver's ProviderConfigs, detecting any
+// that share the same (Name, Alias) pair.
+//
+// Terraform requires each provider configuration to be declared exactly
+// once per module, so a repeated (Name, Alias) pair is always an error,
+// pointing at both the original and the duplicate declaration.
+func (m *Module) mergeProviderBlocks(providers []*Provider) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for _, p := range providers {
+		key := p.moduleUniqueKey()
+		if existing, exists := m.ProviderConfigs[key]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate provider configuration",
+				Detail:   fmt.Sprintf("A provider configuration for %q was already given. Each provider configuration may be declared only once per module.", key),
+				Subject:  &p.DeclRange,
+				Context:  &existing.DeclRange,
+			})
+			continue
+		}
+
+		dup := *p
+		m.ProviderConfigs[key] = &dup
+	}
+
+	return diags
+}
+
+// mergeRequiredProviders decodes each of the given "required_providers"
+// blocks, gathered across every file in a module, and folds their
+// requirements into the receiver. A local name that's declared more than
+// once is an error if the two declarations disagree about the provider's
+// source address; otherwise their version constraints are unioned, as
+// are those of any other local name that resolves to the same source
+// address (for example, two differently-aliased entries for one
+// provider type).
+func (m *Module) mergeRequiredProviders(blocks []*hcl.Block) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for _, block := range blocks {
+		reqs, reqsDiags := decodeRequiredProvidersBlock(block)
+		diags = append(diags, reqsDiags...)
+		diags = append(diags, m.appendProviderRequirements(reqs)...)
+	}
+
+	return diags
+}
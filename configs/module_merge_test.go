@@ -0,0 +1,175 @@
+package configs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func mustRequiredProvidersBlock(t *testing.T, filename, src string) *hcl.Block {
+	t.Helper()
+	f, diags := hclsyntax.ParseConfig([]byte(src), filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected parse errors in %s: %s", filename, diags)
+	}
+	content, diags := f.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "required_providers"}},
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected content errors in %s: %s", filename, diags)
+	}
+	return content.Blocks[0]
+}
+
+func TestModuleMergeRequiredProviders_acrossFiles(t *testing.T) {
+	blockA := mustRequiredProvidersBlock(t, "a.tf", `
+required_providers {
+  foo = {
+    source  = "hashicorp/test"
+    version = "~> 1.0"
+  }
+}
+`)
+	blockB := mustRequiredProvidersBlock(t, "b.tf", `
+required_providers {
+  bar = {
+    source  = "hashicorp/test"
+    version = "~> 2.0"
+  }
+}
+`)
+
+	m := NewModule()
+	diags := m.mergeRequiredProviders([]*hcl.Block{blockA, blockB})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diags: %s", diags)
+	}
+
+	wantAddr := addrs.ProviderSourceAddr{
+		Hostname:  addrs.DefaultProviderSourceHost,
+		Namespace: "hashicorp",
+		Type:      "test",
+	}
+	req, ok := m.ProviderRequirements[wantAddr]
+	if !ok {
+		t.Fatalf("no merged requirement for %s", wantAddr)
+	}
+	if got, want := len(req.VersionConstraints), 2; got != want {
+		t.Fatalf("wrong number of merged version constraints: got %d, want %d", got, want)
+	}
+	for _, name := range []string{"foo", "bar"} {
+		if got := m.ProviderLocalNames[name]; got != wantAddr {
+			t.Errorf("wrong source address for local name %q: got %s, want %s", name, got, wantAddr)
+		}
+	}
+}
+
+func TestModuleMergeRequiredProviders_conflictingSourceAcrossFiles(t *testing.T) {
+	blockA := mustRequiredProvidersBlock(t, "a.tf", `
+required_providers {
+  foo = {
+    source = "hashicorp/test"
+  }
+}
+`)
+	blockB := mustRequiredProvidersBlock(t, "b.tf", `
+required_providers {
+  foo = {
+    source = "othercorp/test"
+  }
+}
+`)
+
+	m := NewModule()
+	diags := m.mergeRequiredProviders([]*hcl.Block{blockA, blockB})
+	if !diags.HasErrors() {
+		t.Fatalf("expected an error for conflicting sources under the same local name across files")
+	}
+}
+
+func TestModuleMergeProviderBlocks_noDuplicates(t *testing.T) {
+	f, diags := hclsyntax.ParseConfig([]byte(`
+provider "foo" {
+  region = "a"
+}
+provider "foo" {
+  alias  = "west"
+  region = "b"
+}
+`), "providers.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", diags)
+	}
+	content, diags := f.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "provider", LabelNames: []string{"name"}}},
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected content errors: %s", diags)
+	}
+
+	var providers []*Provider
+	for _, block := range content.Blocks {
+		p, pDiags := decodeProviderBlock(block)
+		diags = append(diags, pDiags...)
+		providers = append(providers, p)
+	}
+	if diags.HasErrors() {
+		t.Fatalf("unexpected decode diags: %s", diags)
+	}
+
+	m := NewModule()
+	mergeDiags := m.mergeProviderBlocks(providers)
+	if mergeDiags.HasErrors() {
+		t.Fatalf("unexpected merge diags: %s", mergeDiags)
+	}
+	if got, want := len(m.ProviderConfigs), 2; got != want {
+		t.Fatalf("wrong number of merged provider configs: got %d, want %d", got, want)
+	}
+}
+
+func TestModuleMergeProviderBlocks_duplicate(t *testing.T) {
+	f, diags := hclsyntax.ParseConfig([]byte(`
+provider "foo" {
+  region = "a"
+}
+provider "foo" {
+  region = "b"
+}
+`), "providers.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", diags)
+	}
+	content, diags := f.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "provider", LabelNames: []string{"name"}}},
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected content errors: %s", diags)
+	}
+
+	var providers []*Provider
+	for _, block := range content.Blocks {
+		p, pDiags := decodeProviderBlock(block)
+		diags = append(diags, pDiags...)
+		providers = append(providers, p)
+	}
+	if diags.HasErrors() {
+		t.Fatalf("unexpected decode diags: %s", diags)
+	}
+
+	m := NewModule()
+	mergeDiags := m.mergeProviderBlocks(providers)
+	if !mergeDiags.HasErrors() {
+		t.Fatalf("expected an error for a duplicate provider configuration")
+	}
+
+	diag := mergeDiags[0]
+	if diag.Subject == nil || diag.Context == nil {
+		t.Fatalf("expected the diagnostic to carry both the duplicate's and the original's ranges, got Subject=%v Context=%v", diag.Subject, diag.Context)
+	}
+	if *diag.Subject == *diag.Context {
+		t.Fatalf("expected Subject and Context to point at different declarations, got the same range %s", diag.Subject)
+	}
+}
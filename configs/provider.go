@@ -2,16 +2,15 @@ package configs
 
 import (
 	"fmt"
-	"log"
 
-	version "github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/zclconf/go-cty/cty"
-	"github.com/zclconf/go-cty/cty/convert"
 
 	"github.com/hashicorp/terraform/addrs"
+	"github.com/hashicorp/terraform/configs/configschema"
 )
 
 // Provider represents a "provider" block in a module or file. A provider
@@ -101,84 +100,25 @@ func (p *Provider) moduleUniqueKey() string {
 	return p.Name
 }
 
-// ProviderRequirement represents a declaration of a dependency on a particular
-// provider version and source without actually configuring that provider.
-// TODO: Add ranges for diagnostics
-type ProviderRequirement struct {
-	Name               string
-	Source             string
-	VersionConstraints []VersionConstraint
-}
-
-func decodeRequiredProvidersBlock(block *hcl.Block) ([]*ProviderRequirement, hcl.Diagnostics) {
-	attrs, diags := block.Body.JustAttributes()
-	var reqs []*ProviderRequirement
-	for name, attr := range attrs {
-		expr, err := attr.Expr.Value(nil)
-		if err != nil {
-			log.Printf("[TRACE] expr err in decodeRequiredProvidersBlock: %s\n", err.Error())
-			panic("buhbye")
-		}
-		if expr.Type().IsPrimitiveType() {
-			req, reqDiags := decodeVersionConstraint(attr)
-			diags = append(diags, reqDiags...)
-			if !diags.HasErrors() {
-				reqs = append(reqs, &ProviderRequirement{
-					Name:               name,
-					VersionConstraints: []VersionConstraint{req},
-				})
-			}
-		} else if expr.Type().IsObjectType() {
-			// This is incomplete: the "name" here is the user-supplied map key, not the type name
-			pr := &ProviderRequirement{Name: name}
-			if expr.Type().HasAttribute("version") {
-				constraintStr, err := version.NewConstraint(expr.GetAttr("version").AsString())
-				if err != nil {
-					// NewConstraint doesn't return user-friendly errors, so we'll just
-					// ignore the provided error and produce our own generic one.
-					versionDiags := &hcl.Diagnostic{
-						Severity: hcl.DiagError,
-						Summary:  "Invalid version constraint",
-						Detail:   "This string does not use correct version constraint syntax.", // Not very actionable :(
-						Subject:  attr.Expr.Range().Ptr(),
-					}
-					diags = append(diags, versionDiags)
-				}
-				vc := VersionConstraint{
-					DeclRange: attr.Range,
-					Required:  constraintStr,
-				}
-				pr.VersionConstraints = append(pr.VersionConstraints, vc)
-			}
-			if expr.Type().HasAttribute("source") {
-				pr.Source = expr.GetAttr("source").AsString()
-			}
-			reqs = append(reqs, pr)
-		}
-	}
-
-	return reqs, diags
-}
-
-func (pr *ProviderRequirement) decodeProviderSource(attr *hcl.Attribute) (diags hcl.Diagnostics) {
-	val, diags := attr.Expr.Value(nil)
-	if diags.HasErrors() {
-		diags = append(diags, diags...)
-		return
-	}
-	var err error
-	val, err = convert.Convert(val, cty.String)
-	if err != nil {
-		diags = append(diags, &hcl.Diagnostic{
-			Severity: hcl.DiagError,
-			Summary:  "Invalid source constraint",
-			Detail:   fmt.Sprintf("A string value is required for %s.", attr.Name),
-			Subject:  attr.Expr.Range().Ptr(),
-		})
-		return
+// DecodeConfig decodes the receiver's raw configuration body against the
+// given provider schema, once that schema has become known.
+//
+// This is a separate, later step from decodeProviderBlock because the
+// schema for a provider's configuration isn't known until its plugin has
+// been located and its schema requested, whereas the "provider" block
+// itself must be decoded earlier in order to determine which providers
+// are required in the first place. decodeProviderBlock has already
+// stripped the reserved arguments and block types from Config, so any
+// diagnostics produced here are purely about the plugin-defined schema,
+// and callers should combine them with decodeProviderBlock's diagnostics
+// to present one consolidated set of errors for the provider block.
+func (p *Provider) DecodeConfig(schema *configschema.Block) (cty.Value, hcl.Diagnostics) {
+	spec := schema.DecoderSpec()
+	val, diags := hcldec.Decode(p.Config, spec, nil)
+	if val == cty.NilVal {
+		val = cty.UnknownVal(schema.ImpliedType())
 	}
-	pr.Source = val.AsString()
-	return
+	return val, diags
 }
 
 var providerBlockSchema = &hcl.BodySchema{
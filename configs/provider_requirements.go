@@ -0,0 +1,172 @@
+package configs
+
+import (
+	"fmt"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// ProviderRequirement represents a declaration of a dependency on a
+// particular provider version and source without actually configuring
+// that provider.
+type ProviderRequirement struct {
+	Name       string
+	NameRange  hcl.Range
+	Source     string
+	SourceAddr addrs.ProviderSourceAddr
+
+	// SourceRange is the range of the "source" subexpression, or of the
+	// entry's whole value expression if no "source" was given.
+	SourceRange hcl.Range
+
+	// DeclRange is the range of the whole required_providers entry,
+	// covering both the local name and its value.
+	DeclRange hcl.Range
+
+	VersionConstraints []VersionConstraint
+}
+
+func decodeRequiredProvidersBlock(block *hcl.Block) ([]*ProviderRequirement, hcl.Diagnostics) {
+	attrs, diags := block.Body.JustAttributes()
+	var reqs []*ProviderRequirement
+	for name, attr := range attrs {
+		pr := &ProviderRequirement{
+			Name:        name,
+			NameRange:   attr.NameRange,
+			DeclRange:   attr.Range,
+			SourceRange: attr.Expr.Range(),
+		}
+
+		expr, err := attr.Expr.Value(nil)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid required_providers entry",
+				Detail:   fmt.Sprintf("Unsuitable value for provider requirement %q: %s.", name, err),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+			continue
+		}
+
+		switch {
+		case expr.Type().IsPrimitiveType():
+			req, reqDiags := decodeVersionConstraint(attr)
+			diags = append(diags, reqDiags...)
+			if !reqDiags.HasErrors() {
+				pr.VersionConstraints = []VersionConstraint{req}
+				reqs = append(reqs, pr)
+			}
+
+		case expr.Type().IsObjectType():
+			pairs, pairsDiags := hcl.ExprMap(attr.Expr)
+			diags = append(diags, pairsDiags...)
+			for _, pair := range pairs {
+				keyVal, keyDiags := pair.Key.Value(nil)
+				diags = append(diags, keyDiags...)
+				if keyDiags.HasErrors() || keyVal.IsNull() || !keyVal.Type().Equals(cty.String) {
+					continue
+				}
+
+				switch keyVal.AsString() {
+				case "version":
+					str, ok, strDiags := decodeRequiredProviderString("version", pair.Value)
+					diags = append(diags, strDiags...)
+					if !ok {
+						continue
+					}
+					constraints, err := version.NewConstraint(str)
+					if err != nil {
+						// NewConstraint doesn't return user-friendly errors, so we'll just
+						// ignore the provided error and produce our own generic one.
+						diags = append(diags, &hcl.Diagnostic{
+							Severity: hcl.DiagError,
+							Summary:  "Invalid version constraint",
+							Detail:   "This string does not use correct version constraint syntax.", // Not very actionable :(
+							Subject:  pair.Value.Range().Ptr(),
+						})
+						continue
+					}
+					pr.VersionConstraints = append(pr.VersionConstraints, VersionConstraint{
+						Required:  constraints,
+						DeclRange: pair.Value.Range(),
+					})
+
+				case "source":
+					pr.SourceRange = pair.Value.Range()
+					str, ok, strDiags := decodeRequiredProviderString("source", pair.Value)
+					diags = append(diags, strDiags...)
+					if !ok {
+						continue
+					}
+					pr.Source = str
+					sourceAddr, sourceDiags := addrs.ParseProviderSourceAddr(pr.Source, pair.Value.Range().Ptr())
+					diags = append(diags, sourceDiags...)
+					pr.SourceAddr = sourceAddr
+				}
+			}
+			reqs = append(reqs, pr)
+
+		default:
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid required_providers entry",
+				Detail:   fmt.Sprintf("Provider requirement %q must be either a version constraint string or an object with \"source\" and/or \"version\" arguments.", name),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	return reqs, diags
+}
+
+// decodeRequiredProviderString decodes expr, the value of one key in a
+// required_providers object-form entry, as a string, producing a
+// diagnostic instead of panicking if it's some other type, null, or (once
+// any syntax error diagnostics have had a chance to surface on their own)
+// not wholly known. The second return value is false whenever the string
+// isn't usable, in which case the caller should skip the key rather than
+// use the returned value.
+func decodeRequiredProviderString(what string, expr hcl.Expression) (string, bool, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	val, valDiags := expr.Value(nil)
+	diags = append(diags, valDiags...)
+	if valDiags.HasErrors() {
+		return "", false, diags
+	}
+
+	val, err := convert.Convert(val, cty.String)
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Invalid %s argument", what),
+			Detail:   fmt.Sprintf("A string value is required for %q.", what),
+			Subject:  expr.Range().Ptr(),
+		})
+		return "", false, diags
+	}
+
+	if val.IsNull() {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Invalid %s argument", what),
+			Detail:   fmt.Sprintf("The %q argument must not be null.", what),
+			Subject:  expr.Range().Ptr(),
+		})
+		return "", false, diags
+	}
+
+	if !val.IsWhollyKnown() {
+		// If there is a syntax error, HCL sets the value of the given
+		// attribute to cty.DynamicVal. A diagnostic for the syntax error
+		// will already bubble up, so we will move forward gracefully here.
+		return "", false, diags
+	}
+
+	return val.AsString(), true, diags
+}
@@ -0,0 +1,182 @@
+package configs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestAppendProviderRequirements_aliasedSource(t *testing.T) {
+	src := `
+required_providers {
+  foo = {
+    source  = "hashicorp/test"
+    version = "~> 1.0"
+  }
+  bar = {
+    source  = "hashicorp/test"
+    version = "~> 2.0"
+  }
+}
+`
+	f, parseDiags := hclsyntax.ParseConfig([]byte(src), "test.tf", hcl.InitialPos)
+	if parseDiags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", parseDiags)
+	}
+	content, contentDiags := f.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "required_providers"}},
+	})
+	if contentDiags.HasErrors() {
+		t.Fatalf("unexpected content errors: %s", contentDiags)
+	}
+
+	reqs, diags := decodeRequiredProvidersBlock(content.Blocks[0])
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diags: %s", diags)
+	}
+	if got, want := len(reqs), 2; got != want {
+		t.Fatalf("wrong number of decoded requirements: got %d, want %d", got, want)
+	}
+
+	m := NewModule()
+	diags = append(diags, m.appendProviderRequirements(reqs)...)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected merge diags: %s", diags)
+	}
+
+	wantAddr := addrs.ProviderSourceAddr{
+		Hostname:  addrs.DefaultProviderSourceHost,
+		Namespace: "hashicorp",
+		Type:      "test",
+	}
+
+	req, ok := m.ProviderRequirements[wantAddr]
+	if !ok {
+		t.Fatalf("no merged requirement for %s", wantAddr)
+	}
+	if got, want := len(req.VersionConstraints), 2; got != want {
+		t.Fatalf("wrong number of merged version constraints: got %d, want %d", got, want)
+	}
+
+	for _, name := range []string{"foo", "bar"} {
+		if got := m.ProviderLocalNames[name]; got != wantAddr {
+			t.Errorf("wrong source address for local name %q: got %s, want %s", name, got, wantAddr)
+		}
+	}
+}
+
+func TestAppendProviderRequirements_conflictingSource(t *testing.T) {
+	m := NewModule()
+	reqs := []*ProviderRequirement{
+		{
+			Name:       "foo",
+			Source:     "hashicorp/test",
+			SourceAddr: addrs.ProviderSourceAddr{Hostname: addrs.DefaultProviderSourceHost, Namespace: "hashicorp", Type: "test"},
+		},
+		{
+			Name:       "foo",
+			Source:     "othercorp/test",
+			SourceAddr: addrs.ProviderSourceAddr{Hostname: addrs.DefaultProviderSourceHost, Namespace: "othercorp", Type: "test"},
+		},
+	}
+
+	diags := m.appendProviderRequirements(reqs)
+	if !diags.HasErrors() {
+		t.Fatalf("expected an error for conflicting sources under the same local name")
+	}
+}
+
+func TestAppendProviderRequirements_implicitSourceCaseNormalization(t *testing.T) {
+	src := `
+required_providers {
+  Foo = "~> 1.0"
+}
+`
+	f, parseDiags := hclsyntax.ParseConfig([]byte(src), "test.tf", hcl.InitialPos)
+	if parseDiags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", parseDiags)
+	}
+	content, contentDiags := f.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "required_providers"}},
+	})
+	if contentDiags.HasErrors() {
+		t.Fatalf("unexpected content errors: %s", contentDiags)
+	}
+
+	reqs, diags := decodeRequiredProvidersBlock(content.Blocks[0])
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diags: %s", diags)
+	}
+
+	m := NewModule()
+	diags = append(diags, m.appendProviderRequirements(reqs)...)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected merge diags: %s", diags)
+	}
+
+	// A second, explicit requirement for the same (lower-case) provider
+	// type should merge with the implicit one derived from "Foo" above,
+	// rather than being treated as a distinct provider.
+	explicit := []*ProviderRequirement{
+		{
+			Name:               "foo2",
+			Source:             "hashicorp/foo",
+			SourceAddr:         addrs.ProviderSourceAddr{Hostname: addrs.DefaultProviderSourceHost, Namespace: "hashicorp", Type: "foo"},
+			VersionConstraints: []VersionConstraint{{DeclRange: hcl.Range{Filename: "explicit.tf"}}},
+		},
+	}
+	diags = m.appendProviderRequirements(explicit)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected merge diags: %s", diags)
+	}
+
+	wantAddr := addrs.ProviderSourceAddr{
+		Hostname:  addrs.DefaultProviderSourceHost,
+		Namespace: addrs.DefaultProviderSourceNamespace,
+		Type:      "foo",
+	}
+	req, ok := m.ProviderRequirements[wantAddr]
+	if !ok {
+		t.Fatalf("no merged requirement for %s", wantAddr)
+	}
+	if got, want := len(req.VersionConstraints), 2; got != want {
+		t.Fatalf("wrong number of merged version constraints: got %d, want %d", got, want)
+	}
+	if got := m.ProviderLocalNames["Foo"]; got != wantAddr {
+		t.Errorf("wrong source address for local name %q: got %s, want %s", "Foo", got, wantAddr)
+	}
+}
+
+func TestDecodeRequiredProvidersBlock_invalidValueTypes(t *testing.T) {
+	src := `
+required_providers {
+  foo = {
+    version = null
+  }
+  bar = {
+    source = [1, 2, 3]
+  }
+}
+`
+	f, parseDiags := hclsyntax.ParseConfig([]byte(src), "test.tf", hcl.InitialPos)
+	if parseDiags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", parseDiags)
+	}
+	content, contentDiags := f.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "required_providers"}},
+	})
+	if contentDiags.HasErrors() {
+		t.Fatalf("unexpected content errors: %s", contentDiags)
+	}
+
+	// This must not panic: a null "version" and a non-string "source" are
+	// both syntactically valid HCL that should surface as diagnostics,
+	// not crash the decoder.
+	_, diags := decodeRequiredProvidersBlock(content.Blocks[0])
+	if !diags.HasErrors() {
+		t.Fatalf("expected diagnostics for a null version and a non-string source")
+	}
+}
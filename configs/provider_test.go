@@ -0,0 +1,115 @@
+package configs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/terraform/configs/configschema"
+)
+
+func mustDecodeProviderBlock(t *testing.T, src string) *Provider {
+	t.Helper()
+	f, diags := hclsyntax.ParseConfig([]byte(src), "provider.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", diags)
+	}
+	content, diags := f.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "provider", LabelNames: []string{"name"}}},
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected content errors: %s", diags)
+	}
+
+	p, diags := decodeProviderBlock(content.Blocks[0])
+	if diags.HasErrors() {
+		t.Fatalf("unexpected decode errors: %s", diags)
+	}
+	return p
+}
+
+func TestProviderDecodeConfig_valid(t *testing.T) {
+	p := mustDecodeProviderBlock(t, `
+provider "foo" {
+  region = "us-east-1"
+}
+`)
+
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"region": {Type: cty.String, Required: true},
+		},
+	}
+
+	val, diags := p.DecodeConfig(schema)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diags: %s", diags)
+	}
+	if got, want := val.GetAttr("region"), cty.StringVal("us-east-1"); !got.RawEquals(want) {
+		t.Errorf("wrong region value: got %#v, want %#v", got, want)
+	}
+}
+
+func TestProviderDecodeConfig_unsupportedArgument(t *testing.T) {
+	p := mustDecodeProviderBlock(t, `
+provider "foo" {
+  region    = "us-east-1"
+  not_in_schema = "oops"
+}
+`)
+
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"region": {Type: cty.String, Required: true},
+		},
+	}
+
+	_, diags := p.DecodeConfig(schema)
+	if !diags.HasErrors() {
+		t.Fatalf("expected an error for an argument the schema doesn't define")
+	}
+}
+
+func TestProviderDecodeConfig_typeMismatch(t *testing.T) {
+	p := mustDecodeProviderBlock(t, `
+provider "foo" {
+  region = ["us-east-1"]
+}
+`)
+
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"region": {Type: cty.String, Required: true},
+		},
+	}
+
+	_, diags := p.DecodeConfig(schema)
+	if !diags.HasErrors() {
+		t.Fatalf("expected an error for a value of the wrong type")
+	}
+}
+
+func TestProviderDecodeConfig_undefinedNestedBlock(t *testing.T) {
+	p := mustDecodeProviderBlock(t, `
+provider "foo" {
+  region = "us-east-1"
+
+  endpoints {
+    s3 = "http://localhost:4572"
+  }
+}
+`)
+
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"region": {Type: cty.String, Required: true},
+		},
+	}
+
+	_, diags := p.DecodeConfig(schema)
+	if !diags.HasErrors() {
+		t.Fatalf("expected an error for a nested block type the schema doesn't define")
+	}
+}
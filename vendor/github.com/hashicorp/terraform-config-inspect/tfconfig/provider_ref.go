@@ -2,11 +2,11 @@ package tfconfig
 
 import (
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/convert"
 )
@@ -23,9 +23,150 @@ type ProviderRequirement struct {
 	Name               string
 	Alias              string
 	Source             string
+	SourceAddr         ProviderSourceAddr `json:"-"`
 	VersionConstraints []VersionConstraint
 }
 
+// DefaultProviderSourceHost and DefaultProviderSourceNamespace are
+// substituted into a provider source address when the corresponding
+// component is omitted from the source string.
+const (
+	DefaultProviderSourceHost      = "registry.terraform.io"
+	DefaultProviderSourceNamespace = "hashicorp"
+)
+
+// ProviderSourceAddr is a parsed provider source address, fully qualified
+// to the three-part "hostname/namespace/type" form. It mirrors
+// addrs.ProviderSourceAddr in the main Terraform module, but is defined
+// separately here so that tfconfig has no dependency on Terraform itself.
+type ProviderSourceAddr struct {
+	Hostname  string
+	Namespace string
+	Type      string
+}
+
+func (p ProviderSourceAddr) String() string {
+	return fmt.Sprintf("%s/%s/%s", p.Hostname, p.Namespace, p.Type)
+}
+
+// parseProviderSourceAddr parses a "source" string into a
+// ProviderSourceAddr, defaulting the hostname and namespace when they are
+// omitted, validating that each component is a valid DNS label /
+// identifier, and normalizing the result to lowercase so that two source
+// strings differing only in case are treated as the same provider.
+//
+// subject, if non-nil, is used as the diagnostic subject range so callers
+// can point at the "source" subexpression that produced an invalid
+// address.
+func parseProviderSourceAddr(source string, subject *hcl.Range) (ProviderSourceAddr, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	parts := strings.Split(source, "/")
+	for _, part := range parts {
+		if part == "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid provider source address",
+				Detail:   fmt.Sprintf("Provider source %q must not have empty path segments.", source),
+				Subject:  subject,
+			})
+			return ProviderSourceAddr{}, diags
+		}
+	}
+
+	var addr ProviderSourceAddr
+	switch len(parts) {
+	case 1:
+		addr = ProviderSourceAddr{
+			Hostname:  DefaultProviderSourceHost,
+			Namespace: DefaultProviderSourceNamespace,
+			Type:      parts[0],
+		}
+	case 2:
+		addr = ProviderSourceAddr{
+			Hostname:  DefaultProviderSourceHost,
+			Namespace: parts[0],
+			Type:      parts[1],
+		}
+	case 3:
+		addr = ProviderSourceAddr{
+			Hostname:  parts[0],
+			Namespace: parts[1],
+			Type:      parts[2],
+		}
+	default:
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider source address",
+			Detail:   fmt.Sprintf("Provider source %q must have the form \"[hostname/][namespace/]type\".", source),
+			Subject:  subject,
+		})
+		return ProviderSourceAddr{}, diags
+	}
+
+	if !validProviderSourceHostname(addr.Hostname) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider source address",
+			Detail:   fmt.Sprintf("Provider source %q has invalid hostname %q: must be a valid DNS name.", source, addr.Hostname),
+			Subject:  subject,
+		})
+	}
+	if !hclsyntax.ValidIdentifier(addr.Namespace) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider source address",
+			Detail:   fmt.Sprintf("Provider source %q has invalid namespace %q: must be a valid identifier.", source, addr.Namespace),
+			Subject:  subject,
+		})
+	}
+	if !hclsyntax.ValidIdentifier(addr.Type) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider source address",
+			Detail:   fmt.Sprintf("Provider source %q has invalid provider type %q: must be a valid identifier.", source, addr.Type),
+			Subject:  subject,
+		})
+	}
+
+	addr.Hostname = strings.ToLower(addr.Hostname)
+	addr.Namespace = strings.ToLower(addr.Namespace)
+	addr.Type = strings.ToLower(addr.Type)
+
+	return addr, diags
+}
+
+func validProviderSourceHostname(host string) bool {
+	labels := strings.Split(host, ".")
+	if len(labels) == 0 {
+		return false
+	}
+	for _, label := range labels {
+		if !validDNSLabel(label) {
+			return false
+		}
+	}
+	return true
+}
+
+func validDNSLabel(label string) bool {
+	if label == "" {
+		return false
+	}
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			continue
+		case c == '-' && i != 0 && i != len(label)-1:
+			continue
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 type VersionConstraint struct {
 	Required  version.Constraints
 	DeclRange hcl.Range
@@ -37,13 +178,18 @@ func decodeRequiredProvidersBlock(block *hcl.Block) (map[string]*ProviderRequire
 	for name, attr := range attrs {
 		expr, err := attr.Expr.Value(nil)
 		if err != nil {
-			log.Printf("[TRACE] expr err in decodeRequiredProvidersBlock: %s\n", err.Error())
-			panic("TODO put real error here")
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid required_providers entry",
+				Detail:   fmt.Sprintf("Unsuitable value for provider requirement %q: %s.", name, err),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+			continue
 		}
 		if expr.Type().IsPrimitiveType() {
 			req, reqDiags := decodeVersionConstraint(attr)
 			diags = append(diags, reqDiags...)
-			if !diags.HasErrors() {
+			if !reqDiags.HasErrors() {
 				reqs[name] = &ProviderRequirement{
 					Name:               name,
 					VersionConstraints: []VersionConstraint{req},
@@ -51,31 +197,38 @@ func decodeRequiredProvidersBlock(block *hcl.Block) (map[string]*ProviderRequire
 			}
 		} else if expr.Type().IsObjectType() {
 			pr := &ProviderRequirement{}
-			// typeName := name
 			if expr.Type().HasAttribute("version") {
-				constraintStr, err := version.NewConstraint(expr.GetAttr("version").AsString())
-				if err != nil {
-					// NewConstraint doesn't return user-friendly errors, so we'll just
-					// ignore the provided error and produce our own generic one.
-					versionDiags := &hcl.Diagnostic{
-						Severity: hcl.DiagError,
-						Summary:  "Invalid version constraint",
-						Detail:   "This string does not use correct version constraint syntax.", // Not very actionable :(
-						Subject:  attr.Expr.Range().Ptr(),
+				str, ok, strDiags := decodeRequiredProviderString("version", expr.GetAttr("version"), attr.Expr.Range())
+				diags = append(diags, strDiags...)
+				if ok {
+					constraints, err := version.NewConstraint(str)
+					if err != nil {
+						// NewConstraint doesn't return user-friendly errors, so we'll just
+						// ignore the provided error and produce our own generic one.
+						diags = append(diags, &hcl.Diagnostic{
+							Severity: hcl.DiagError,
+							Summary:  "Invalid version constraint",
+							Detail:   "This string does not use correct version constraint syntax.", // Not very actionable :(
+							Subject:  attr.Expr.Range().Ptr(),
+						})
+					} else {
+						pr.VersionConstraints = append(pr.VersionConstraints, VersionConstraint{
+							DeclRange: attr.Range,
+							Required:  constraints,
+						})
 					}
-					diags = append(diags, versionDiags)
 				}
-				vc := VersionConstraint{
-					DeclRange: attr.Range,
-					Required:  constraintStr,
-				}
-				pr.VersionConstraints = append(pr.VersionConstraints, vc)
 			}
 			if expr.Type().HasAttribute("source") {
-				sourceStr := expr.GetAttr("source").AsString()
-				typeName := typeNameFromSource(sourceStr)
-				pr.Source = sourceStr
-				pr.Name = typeName
+				sourceStr, ok, strDiags := decodeRequiredProviderString("source", expr.GetAttr("source"), attr.Expr.Range())
+				diags = append(diags, strDiags...)
+				if ok {
+					sourceAddr, sourceDiags := parseProviderSourceAddr(sourceStr, attr.Expr.Range().Ptr())
+					diags = append(diags, sourceDiags...)
+					pr.Source = sourceStr
+					pr.SourceAddr = sourceAddr
+					pr.Name = sourceAddr.Type
+				}
 				pr.Alias = name
 			} else {
 				pr.Name = name
@@ -87,6 +240,51 @@ func decodeRequiredProvidersBlock(block *hcl.Block) (map[string]*ProviderRequire
 	return reqs, diags
 }
 
+// decodeRequiredProviderString validates and converts the value of a
+// "version" or "source" key from a required_providers object-form entry
+// as a string, producing a diagnostic instead of panicking if it's some
+// other type, null, or (once any syntax error diagnostics have had a
+// chance to surface on their own) not wholly known.
+//
+// rng is used as the diagnostic subject range, since the object-literal
+// values decoded here don't carry their own subexpression ranges. The
+// second return value is false whenever the string isn't usable, in
+// which case the caller should skip the key rather than use the
+// returned value.
+func decodeRequiredProviderString(name string, val cty.Value, rng hcl.Range) (string, bool, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	val, err := convert.Convert(val, cty.String)
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Invalid %s argument", name),
+			Detail:   fmt.Sprintf("A string value is required for %q.", name),
+			Subject:  rng.Ptr(),
+		})
+		return "", false, diags
+	}
+
+	if val.IsNull() {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Invalid %s argument", name),
+			Detail:   fmt.Sprintf("The %q argument must not be null.", name),
+			Subject:  rng.Ptr(),
+		})
+		return "", false, diags
+	}
+
+	if !val.IsWhollyKnown() {
+		// If there is a syntax error, HCL sets the value of the given
+		// attribute to cty.DynamicVal. A diagnostic for the syntax error
+		// will already bubble up, so we will move forward gracefully here.
+		return "", false, diags
+	}
+
+	return val.AsString(), true, diags
+}
+
 func decodeVersionConstraint(attr *hcl.Attribute) (VersionConstraint, hcl.Diagnostics) {
 	ret := VersionConstraint{
 		DeclRange: attr.Range,
@@ -138,8 +336,3 @@ func decodeVersionConstraint(attr *hcl.Attribute) (VersionConstraint, hcl.Diagno
 	ret.Required = constraints
 	return ret, diags
 }
-
-func typeNameFromSource(source string) string {
-	parts := strings.Split(source, "/")
-	return parts[len(parts)-1]
-}
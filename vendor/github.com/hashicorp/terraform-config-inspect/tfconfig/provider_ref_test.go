@@ -0,0 +1,94 @@
+package tfconfig
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestParseProviderSourceAddr_caseNormalization(t *testing.T) {
+	got, diags := parseProviderSourceAddr("Hashicorp/Test", nil)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diags: %s", diags)
+	}
+
+	want, diags := parseProviderSourceAddr("hashicorp/test", nil)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diags: %s", diags)
+	}
+
+	if got != want {
+		t.Fatalf("differently-cased source addresses did not normalize to the same value: got %#v, want %#v", got, want)
+	}
+}
+
+func TestParseProviderSourceAddr_invalid(t *testing.T) {
+	_, diags := parseProviderSourceAddr("not a valid hostname/test/test", nil)
+	if !diags.HasErrors() {
+		t.Fatalf("expected an error for an invalid hostname")
+	}
+}
+
+func mustRequiredProvidersBlock(t *testing.T, src string) *hcl.Block {
+	t.Helper()
+	f, diags := hclsyntax.ParseConfig([]byte(src), "test.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", diags)
+	}
+	content, diags := f.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "required_providers"}},
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected content errors: %s", diags)
+	}
+	return content.Blocks[0]
+}
+
+func TestDecodeRequiredProvidersBlock(t *testing.T) {
+	block := mustRequiredProvidersBlock(t, `
+required_providers {
+  foo = {
+    source  = "Hashicorp/Test"
+    version = "~> 1.0"
+  }
+}
+`)
+
+	reqs, diags := decodeRequiredProvidersBlock(block)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diags: %s", diags)
+	}
+
+	req, ok := reqs["foo"]
+	if !ok {
+		t.Fatalf("no requirement decoded for \"foo\"")
+	}
+	if got, want := req.SourceAddr, (ProviderSourceAddr{Hostname: DefaultProviderSourceHost, Namespace: "hashicorp", Type: "test"}); got != want {
+		t.Fatalf("wrong source address: got %#v, want %#v", got, want)
+	}
+	if got, want := req.Name, "test"; got != want {
+		t.Errorf("wrong type name: got %q, want %q", got, want)
+	}
+}
+
+func TestDecodeRequiredProvidersBlock_invalidValueTypes(t *testing.T) {
+	block := mustRequiredProvidersBlock(t, `
+required_providers {
+  foo = {
+    version = null
+  }
+  bar = {
+    source = [1, 2, 3]
+  }
+}
+`)
+
+	// This must not panic: a null "version" and a non-string "source" are
+	// both syntactically valid HCL that should surface as diagnostics,
+	// not crash the decoder.
+	_, diags := decodeRequiredProvidersBlock(block)
+	if !diags.HasErrors() {
+		t.Fatalf("expected diagnostics for a null version and a non-string source")
+	}
+}